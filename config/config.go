@@ -25,8 +25,51 @@ import (
 	valid "github.com/asaskevich/govalidator"
 )
 
-// Conf has Configuration
-var Conf Config
+// Distro families supported as values of ServerInfo.Family.
+// These are shared by the config validator and the scan package's
+// per-family backend dispatch.
+const (
+	RedHat               = "redhat"
+	CentOS               = "centos"
+	Debian               = "debian"
+	Ubuntu               = "ubuntu"
+	Amazon               = "amazon"
+	Oracle               = "oracle"
+	FreeBSD              = "freebsd"
+	Raspbian             = "raspbian"
+	Alpine               = "alpine"
+	OpenSUSE             = "opensuse"
+	SUSEEnterpriseServer = "suse.linux.enterprise.server"
+	Windows              = "windows"
+)
+
+// familyList is the set of families currently supported by vuls's scan
+// backends. Keep in sync with scan.NewOSFamily.
+var familyList = []string{
+	RedHat,
+	CentOS,
+	Debian,
+	Ubuntu,
+	Amazon,
+	Oracle,
+	FreeBSD,
+	Raspbian,
+	Alpine,
+	OpenSUSE,
+	SUSEEnterpriseServer,
+	Windows,
+}
+
+// IsValidFamily returns whether family is one of the supported distro
+// families.
+func IsValidFamily(family string) bool {
+	for _, f := range familyList {
+		if f == family {
+			return true
+		}
+	}
+	return false
+}
 
 //Config is struct of Configuration
 type Config struct {
@@ -34,11 +77,16 @@ type Config struct {
 	DebugSQL bool
 	Lang     string
 
-	Mail    smtpConf
+	Mail    SMTPConf
 	Slack   SlackConf
 	Default ServerInfo
 	Servers map[string]ServerInfo
 
+	// Notifiers is a list of notification backends a scan result fans
+	// out to, each with its own severity threshold. notify.NewNotifiers
+	// builds one notify.Notifier per entry.
+	Notifiers []NotifierConf
+
 	CveDictionaryURL string `valid:"url"`
 
 	CvssScoreOver      float64
@@ -58,6 +106,8 @@ type Config struct {
 	AzureKey       string
 	AzureContainer string
 
+	Log LogConf
+
 	//  CpeNames      []string
 	//  SummaryMode          bool
 }
@@ -85,14 +135,32 @@ func (c Config) Validate() bool {
 		errs = append(errs, err)
 	}
 
-	if mailerrs := c.Mail.Validate(); 0 < len(mailerrs) {
+	if mailerrs := c.Mail.Validate(false); 0 < len(mailerrs) {
 		errs = append(errs, mailerrs...)
 	}
 
-	if slackerrs := c.Slack.Validate(); 0 < len(slackerrs) {
+	if slackerrs := c.Slack.Validate(false); 0 < len(slackerrs) {
 		errs = append(errs, slackerrs...)
 	}
 
+	if logerrs := c.Log.Validate(); 0 < len(logerrs) {
+		errs = append(errs, logerrs...)
+	}
+
+	for i := range c.Notifiers {
+		if notifyerrs := c.Notifiers[i].Validate(); 0 < len(notifyerrs) {
+			errs = append(errs, notifyerrs...)
+		}
+	}
+
+	for name, server := range c.Servers {
+		if serverrs := server.Validate(); 0 < len(serverrs) {
+			for _, e := range serverrs {
+				errs = append(errs, fmt.Errorf("server: %s, %s", name, e))
+			}
+		}
+	}
+
 	for _, err := range errs {
 		log.Error(err)
 	}
@@ -100,8 +168,75 @@ func (c Config) Validate() bool {
 	return len(errs) == 0
 }
 
-// smtpConf is smtp config
-type smtpConf struct {
+// Notifier backend discriminators for NotifierConf.Type
+const (
+	NotifierSMTP    = "smtp"
+	NotifierSlack   = "slack"
+	NotifierWebhook = "webhook"
+	NotifierGitHub  = "github"
+)
+
+// NotifierConf configures one notify.Notifier backend. Type selects
+// which of the nested *Conf fields is populated.
+type NotifierConf struct {
+	Type string
+
+	CvssScoreOver float64
+
+	SMTP    *SMTPConf
+	Slack   *SlackConf
+	Webhook *WebhookConf
+	GitHub  *GitHubConf
+}
+
+// Validate validates a NotifierConf
+func (c *NotifierConf) Validate() (errs []error) {
+	switch c.Type {
+	case NotifierSMTP:
+		if c.SMTP == nil {
+			errs = append(errs, fmt.Errorf("smtp notifier requires an smtp config block"))
+			return
+		}
+		errs = append(errs, c.SMTP.Validate(true)...)
+	case NotifierSlack:
+		if c.Slack == nil {
+			errs = append(errs, fmt.Errorf("slack notifier requires a slack config block"))
+			return
+		}
+		errs = append(errs, c.Slack.Validate(true)...)
+	case NotifierWebhook:
+		if c.Webhook == nil || len(c.Webhook.URL) == 0 {
+			errs = append(errs, fmt.Errorf("webhook notifier requires a url"))
+			return
+		}
+		if _, err := valid.ValidateStruct(c.Webhook); err != nil {
+			errs = append(errs, err)
+		}
+	case NotifierGitHub:
+		if c.GitHub == nil || len(c.GitHub.Token) == 0 || len(c.GitHub.Repository) == 0 || len(c.GitHub.Owner) == 0 {
+			errs = append(errs, fmt.Errorf("github notifier requires an owner, token and repository"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("Unknown notifier type: %s", c.Type))
+	}
+	return
+}
+
+// WebhookConf is generic JSON HTTP webhook config
+type WebhookConf struct {
+	URL string `valid:"url"`
+}
+
+// GitHubConf is GitHub Issues notifier config. One issue is
+// opened/updated per CVE.
+type GitHubConf struct {
+	Token      string
+	Owner      string
+	Repository string
+}
+
+// SMTPConf is smtp config
+type SMTPConf struct {
 	SMTPAddr string
 	SMTPPort string `valid:"port"`
 
@@ -112,9 +247,21 @@ type smtpConf struct {
 	Cc            []string
 	SubjectPrefix string
 
+	// AuthMechanism selects the SMTP auth mechanism: "PLAIN", "LOGIN",
+	// or "CRAM-MD5". Defaults to "PLAIN" when empty. LOGIN is required
+	// by Office365 and some older appliances that don't speak PLAIN.
+	AuthMechanism string
+
 	UseThisTime bool
 }
 
+// SMTP auth mechanisms supported by SMTPConf.AuthMechanism
+const (
+	SMTPAuthPlain   = "PLAIN"
+	SMTPAuthLogin   = "LOGIN"
+	SMTPAuthCRAMMD5 = "CRAM-MD5"
+)
+
 func checkEmails(emails []string) (errs []error) {
 	for _, addr := range emails {
 		if len(addr) == 0 {
@@ -127,10 +274,12 @@ func checkEmails(emails []string) (errs []error) {
 	return
 }
 
-// Validate SMTP configuration
-func (c *smtpConf) Validate() (errs []error) {
+// Validate validates SMTPConf. required forces validation even when
+// UseThisTime is false, so callers like NotifierConf.Validate can
+// require a fully-populated SMTP block without mutating it.
+func (c *SMTPConf) Validate(required bool) (errs []error) {
 
-	if !c.UseThisTime {
+	if !required && !c.UseThisTime {
 		return
 	}
 
@@ -177,10 +326,12 @@ type SlackConf struct {
 	UseThisTime bool
 }
 
-// Validate validates configuration
-func (c *SlackConf) Validate() (errs []error) {
+// Validate validates SlackConf. required forces validation even when
+// UseThisTime is false, so callers like NotifierConf.Validate can
+// require a fully-populated Slack block without mutating it.
+func (c *SlackConf) Validate(required bool) (errs []error) {
 
-	if !c.UseThisTime {
+	if !required && !c.UseThisTime {
 		return
 	}
 
@@ -210,8 +361,18 @@ func (c *SlackConf) Validate() (errs []error) {
 	return
 }
 
+// ServerTypePseudo marks a ServerInfo that has no SSH endpoint to log
+// into but still carries CpeNames to look up in the CVE dictionary,
+// e.g. appliances, network gear, or air-gapped inventories imported
+// from another tool.
+const ServerTypePseudo = "pseudo"
+
 // ServerInfo has SSH Info, additional CPE packages to scan.
 type ServerInfo struct {
+	// Type is empty for a normal SSH-reachable host, or
+	// ServerTypePseudo for a CPE-only entry with no SSH endpoint.
+	Type string
+
 	ServerName  string
 	User        string
 	Host        string
@@ -224,6 +385,11 @@ type ServerInfo struct {
 	// Container Names or IDs
 	Containers []string
 
+	// DockerContext overrides the Docker CLI context (as in `docker
+	// context use`) used to find this host's daemon endpoint. When
+	// empty, the active context from ~/.docker/config.json is used.
+	DockerContext string
+
 	// Optional key-value set that will be outputted to JSON
 	Optional [][]interface{}
 
@@ -233,6 +399,33 @@ type ServerInfo struct {
 	Family          string
 }
 
+// Validate validates ServerInfo
+func (s ServerInfo) Validate() (errs []error) {
+	if len(s.Family) != 0 && !IsValidFamily(s.Family) {
+		errs = append(errs, fmt.Errorf(
+			"Unknown family: %s, Supported families: %s", s.Family, familyList))
+	}
+
+	if s.Type == ServerTypePseudo {
+		if len(s.CpeNames) == 0 {
+			errs = append(errs, fmt.Errorf(
+				"Type %s requires at least one CpeNames entry", ServerTypePseudo))
+		}
+		return
+	}
+
+	if len(s.Host) == 0 {
+		errs = append(errs, fmt.Errorf("Host must not be empty"))
+	}
+	return
+}
+
+// IsPseudo returns whether this ServerInfo is a CPE-only, SSH-less
+// entry (ServerTypePseudo).
+func (s ServerInfo) IsPseudo() bool {
+	return s.Type == ServerTypePseudo
+}
+
 // IsContainer returns whether this ServerInfo is about container
 func (s ServerInfo) IsContainer() bool {
 	return 0 < len(s.Container.ContainerID)
@@ -254,3 +447,77 @@ type Container struct {
 	Name        string
 	Type        string
 }
+
+// LogConf configures where logrus sends its output: a text or JSON log
+// file under Dir, and optionally a syslog sink for remote SIEMs.
+type LogConf struct {
+	Dir    string
+	JSON   bool
+	Syslog *SyslogConf
+}
+
+// Validate validates LogConf
+func (c LogConf) Validate() (errs []error) {
+	if len(c.Dir) != 0 {
+		if ok, _ := valid.IsFilePath(c.Dir); !ok {
+			errs = append(errs, fmt.Errorf(
+				"Log directory must be a *Absolute* file path. Log.Dir: %s", c.Dir))
+		}
+	}
+
+	if c.Syslog != nil {
+		errs = append(errs, c.Syslog.Validate()...)
+	}
+	return
+}
+
+// Syslog facilities accepted by SyslogConf.Facility
+var syslogFacilities = []string{
+	"kern", "user", "mail", "daemon", "auth", "syslog", "lpr", "news",
+	"uucp", "cron", "authpriv", "ftp",
+	"local0", "local1", "local2", "local3", "local4", "local5", "local6", "local7",
+}
+
+// Syslog severities accepted by SyslogConf.Severity
+var syslogSeverities = []string{
+	"emerg", "alert", "crit", "err", "warning", "notice", "info", "debug",
+}
+
+// SyslogConf is RFC5424 syslog config, used to forward scan events to a
+// remote SIEM with proper severity/facility mapping.
+type SyslogConf struct {
+	Protocol string
+	Host     string `valid:"host"`
+	Port     string `valid:"port"`
+	Severity string
+	Facility string
+	Tag      string
+}
+
+// Validate validates SyslogConf
+func (c *SyslogConf) Validate() (errs []error) {
+	if len(c.Severity) != 0 && !contains(syslogSeverities, c.Severity) {
+		errs = append(errs, fmt.Errorf(
+			"Unknown syslog severity: %s, Supported severities: %s", c.Severity, syslogSeverities))
+	}
+
+	if len(c.Facility) != 0 && !contains(syslogFacilities, c.Facility) {
+		errs = append(errs, fmt.Errorf(
+			"Unknown syslog facility: %s, Supported facilities: %s", c.Facility, syslogFacilities))
+	}
+
+	_, err := valid.ValidateStruct(c)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	return
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,444 @@
+/* Vuls - Vulnerability Scanner
+Copyright (C) 2016  Future Architect, Inc. Japan.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package config
+
+import (
+	"sync"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// ConfigState owns the currently active Config and lets long-running
+// subsystems (report, scan loops) pick up edits to the on-disk file
+// without a process restart. CLI flags, environment variables, and the
+// file source are bound through viper with precedence
+// CLI > env > file > default.
+type ConfigState struct {
+	mu       sync.RWMutex
+	current  *Config
+	v        *viper.Viper
+	path     string
+	changeCh chan struct{}
+}
+
+// current is the process-wide ConfigState, replacing the old ad-hoc
+// `var Conf Config` global. It starts out unbound to any file; callers
+// that want file-backed config should build their own ConfigState with
+// NewConfigState and call Reload, or call Bind to point this one at a
+// path.
+var current = NewConfigState("")
+
+// Bind points the process-wide ConfigState at path and loads it,
+// equivalent to what used to be direct assignment to the old Conf
+// global.
+func Bind(path string) error {
+	current = NewConfigState(path)
+	return current.Reload()
+}
+
+// Current returns a copy of the process-wide active Config.
+func Current() Config {
+	return current.Get()
+}
+
+// SetCurrent replaces the process-wide active Config wholesale. This
+// changes the package-level state that every caller of Current sees.
+func SetCurrent(c Config) {
+	current.Set(c)
+}
+
+// NewConfigState builds a ConfigState bound to the TOML/YAML file at
+// path. Call Reload to do the initial parse.
+func NewConfigState(path string) *ConfigState {
+	v := viper.New()
+	v.SetConfigFile(path)
+	v.AutomaticEnv()
+	v.SetEnvPrefix("VULS")
+
+	return &ConfigState{
+		current:  &Config{},
+		v:        v,
+		path:     path,
+		changeCh: make(chan struct{}, 1),
+	}
+}
+
+// BindPFlags binds a pflag.FlagSet so CLI flags take precedence over
+// environment variables and the config file.
+func (cs *ConfigState) BindPFlags(flags *pflag.FlagSet) error {
+	return cs.v.BindPFlags(flags)
+}
+
+// Changes returns a channel that receives a notification when Reload
+// swaps in a new Config. The channel holds at most one pending
+// notification; reloads made while one is pending are coalesced into it.
+func (cs *ConfigState) Changes() <-chan struct{} {
+	return cs.changeCh
+}
+
+// Reload re-parses the on-disk config file and atomically swaps the
+// active Config pointer under an RWMutex.
+func (cs *ConfigState) Reload() error {
+	if err := cs.v.ReadInConfig(); err != nil {
+		return err
+	}
+
+	next := &Config{}
+	if err := cs.v.Unmarshal(next); err != nil {
+		return err
+	}
+
+	cs.mu.Lock()
+	cs.current = next
+	cs.mu.Unlock()
+
+	select {
+	case cs.changeCh <- struct{}{}:
+	default:
+		// a reload notification is already pending
+	}
+
+	log.Infof("Reloaded config: %s", cs.path)
+	return nil
+}
+
+// Get returns a copy of the currently active Config.
+func (cs *ConfigState) Get() Config {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	return *cs.current
+}
+
+// Set replaces the currently active Config wholesale. Intended for
+// tests that need to override values in isolation without mutating
+// package globals.
+func (cs *ConfigState) Set(c Config) {
+	cs.mu.Lock()
+	cs.current = &c
+	cs.mu.Unlock()
+}
+
+// GetDebug returns the current Debug setting.
+func (cs *ConfigState) GetDebug() bool {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	return cs.current.Debug
+}
+
+// SetDebug overrides the current Debug setting.
+func (cs *ConfigState) SetDebug(v bool) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.current.Debug = v
+}
+
+// GetDebugSQL returns the current DebugSQL setting.
+func (cs *ConfigState) GetDebugSQL() bool {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	return cs.current.DebugSQL
+}
+
+// SetDebugSQL overrides the current DebugSQL setting.
+func (cs *ConfigState) SetDebugSQL(v bool) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.current.DebugSQL = v
+}
+
+// GetLang returns the current Lang setting.
+func (cs *ConfigState) GetLang() string {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	return cs.current.Lang
+}
+
+// SetLang overrides the current Lang setting.
+func (cs *ConfigState) SetLang(v string) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.current.Lang = v
+}
+
+// GetMail returns the current Mail (SMTP) setting.
+func (cs *ConfigState) GetMail() SMTPConf {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	return cs.current.Mail
+}
+
+// SetMail overrides the current Mail (SMTP) setting.
+func (cs *ConfigState) SetMail(v SMTPConf) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.current.Mail = v
+}
+
+// GetSlack returns the current Slack setting.
+func (cs *ConfigState) GetSlack() SlackConf {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	return cs.current.Slack
+}
+
+// SetSlack overrides the current Slack setting.
+func (cs *ConfigState) SetSlack(v SlackConf) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.current.Slack = v
+}
+
+// GetServers returns the current Servers setting.
+func (cs *ConfigState) GetServers() map[string]ServerInfo {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	return cs.current.Servers
+}
+
+// SetServers overrides the current Servers setting.
+func (cs *ConfigState) SetServers(v map[string]ServerInfo) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.current.Servers = v
+}
+
+// GetCveDictionaryURL returns the current CveDictionaryURL setting.
+func (cs *ConfigState) GetCveDictionaryURL() string {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	return cs.current.CveDictionaryURL
+}
+
+// SetCveDictionaryURL overrides the current CveDictionaryURL setting.
+func (cs *ConfigState) SetCveDictionaryURL(v string) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.current.CveDictionaryURL = v
+}
+
+// GetCvssScoreOver returns the current CvssScoreOver setting.
+func (cs *ConfigState) GetCvssScoreOver() float64 {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	return cs.current.CvssScoreOver
+}
+
+// SetCvssScoreOver overrides the current CvssScoreOver setting.
+func (cs *ConfigState) SetCvssScoreOver(v float64) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.current.CvssScoreOver = v
+}
+
+// GetIgnoreUnscoredCves returns the current IgnoreUnscoredCves setting.
+func (cs *ConfigState) GetIgnoreUnscoredCves() bool {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	return cs.current.IgnoreUnscoredCves
+}
+
+// SetIgnoreUnscoredCves overrides the current IgnoreUnscoredCves setting.
+func (cs *ConfigState) SetIgnoreUnscoredCves(v bool) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.current.IgnoreUnscoredCves = v
+}
+
+// GetSSHExternal returns the current SSHExternal setting.
+func (cs *ConfigState) GetSSHExternal() bool {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	return cs.current.SSHExternal
+}
+
+// SetSSHExternal overrides the current SSHExternal setting.
+func (cs *ConfigState) SetSSHExternal(v bool) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.current.SSHExternal = v
+}
+
+// GetHTTPProxy returns the current HTTPProxy setting.
+func (cs *ConfigState) GetHTTPProxy() string {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	return cs.current.HTTPProxy
+}
+
+// SetHTTPProxy overrides the current HTTPProxy setting.
+func (cs *ConfigState) SetHTTPProxy(v string) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.current.HTTPProxy = v
+}
+
+// GetJSONBaseDir returns the current JSONBaseDir setting.
+func (cs *ConfigState) GetJSONBaseDir() string {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	return cs.current.JSONBaseDir
+}
+
+// SetJSONBaseDir overrides the current JSONBaseDir setting.
+func (cs *ConfigState) SetJSONBaseDir(v string) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.current.JSONBaseDir = v
+}
+
+// GetCveDBPath returns the current CveDBPath setting.
+func (cs *ConfigState) GetCveDBPath() string {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	return cs.current.CveDBPath
+}
+
+// SetCveDBPath overrides the current CveDBPath setting.
+func (cs *ConfigState) SetCveDBPath(v string) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.current.CveDBPath = v
+}
+
+// GetAwsProfile returns the current AwsProfile setting.
+func (cs *ConfigState) GetAwsProfile() string {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	return cs.current.AwsProfile
+}
+
+// SetAwsProfile overrides the current AwsProfile setting.
+func (cs *ConfigState) SetAwsProfile(v string) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.current.AwsProfile = v
+}
+
+// GetAwsRegion returns the current AwsRegion setting.
+func (cs *ConfigState) GetAwsRegion() string {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	return cs.current.AwsRegion
+}
+
+// SetAwsRegion overrides the current AwsRegion setting.
+func (cs *ConfigState) SetAwsRegion(v string) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.current.AwsRegion = v
+}
+
+// GetS3Bucket returns the current S3Bucket setting.
+func (cs *ConfigState) GetS3Bucket() string {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	return cs.current.S3Bucket
+}
+
+// SetS3Bucket overrides the current S3Bucket setting.
+func (cs *ConfigState) SetS3Bucket(v string) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.current.S3Bucket = v
+}
+
+// GetAzureAccount returns the current AzureAccount setting.
+func (cs *ConfigState) GetAzureAccount() string {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	return cs.current.AzureAccount
+}
+
+// SetAzureAccount overrides the current AzureAccount setting.
+func (cs *ConfigState) SetAzureAccount(v string) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.current.AzureAccount = v
+}
+
+// GetAzureKey returns the current AzureKey setting.
+func (cs *ConfigState) GetAzureKey() string {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	return cs.current.AzureKey
+}
+
+// SetAzureKey overrides the current AzureKey setting.
+func (cs *ConfigState) SetAzureKey(v string) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.current.AzureKey = v
+}
+
+// GetAzureContainer returns the current AzureContainer setting.
+func (cs *ConfigState) GetAzureContainer() string {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	return cs.current.AzureContainer
+}
+
+// SetAzureContainer overrides the current AzureContainer setting.
+func (cs *ConfigState) SetAzureContainer(v string) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.current.AzureContainer = v
+}
+
+// GetDefault returns the current Default ServerInfo setting.
+func (cs *ConfigState) GetDefault() ServerInfo {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	return cs.current.Default
+}
+
+// SetDefault overrides the current Default ServerInfo setting.
+func (cs *ConfigState) SetDefault(v ServerInfo) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.current.Default = v
+}
+
+// GetLog returns the current Log setting.
+func (cs *ConfigState) GetLog() LogConf {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	return cs.current.Log
+}
+
+// SetLog overrides the current Log setting.
+func (cs *ConfigState) SetLog(v LogConf) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.current.Log = v
+}
+
+// GetNotifiers returns the current Notifiers setting.
+func (cs *ConfigState) GetNotifiers() []NotifierConf {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	return cs.current.Notifiers
+}
+
+// SetNotifiers overrides the current Notifiers setting.
+func (cs *ConfigState) SetNotifiers(v []NotifierConf) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.current.Notifiers = v
+}
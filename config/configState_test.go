@@ -0,0 +1,64 @@
+/* Vuls - Vulnerability Scanner
+Copyright (C) 2016  Future Architect, Inc. Japan.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package config
+
+import "testing"
+
+func TestConfigStateGetSetAccessors(t *testing.T) {
+	cs := NewConfigState("")
+
+	cs.SetDebug(true)
+	if !cs.GetDebug() {
+		t.Error("GetDebug() = false, want true after SetDebug(true)")
+	}
+
+	cs.SetLang("ja")
+	if cs.GetLang() != "ja" {
+		t.Errorf("GetLang() = %q, want %q", cs.GetLang(), "ja")
+	}
+
+	cs.SetCvssScoreOver(7.0)
+	if cs.GetCvssScoreOver() != 7.0 {
+		t.Errorf("GetCvssScoreOver() = %v, want 7.0", cs.GetCvssScoreOver())
+	}
+
+	cs.SetAzureAccount("acct")
+	if cs.GetAzureAccount() != "acct" {
+		t.Errorf("GetAzureAccount() = %q, want %q", cs.GetAzureAccount(), "acct")
+	}
+}
+
+func TestConfigStateSetIsolatedFromOtherInstances(t *testing.T) {
+	a := NewConfigState("")
+	b := NewConfigState("")
+
+	a.SetDebug(true)
+	if b.GetDebug() {
+		t.Error("SetDebug on one ConfigState leaked into another")
+	}
+}
+
+func TestCurrentSetCurrentRoundTrip(t *testing.T) {
+	orig := Current()
+	defer SetCurrent(orig)
+
+	SetCurrent(Config{Lang: "en"})
+	if got := Current().Lang; got != "en" {
+		t.Errorf("Current().Lang = %q, want %q", got, "en")
+	}
+}
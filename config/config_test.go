@@ -0,0 +1,186 @@
+/* Vuls - Vulnerability Scanner
+Copyright (C) 2016  Future Architect, Inc. Japan.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package config
+
+import "testing"
+
+func TestSMTPConfValidateRequiredDoesNotMutateUseThisTime(t *testing.T) {
+	smtp := &SMTPConf{}
+
+	errs := smtp.Validate(true)
+	if len(errs) == 0 {
+		t.Fatal("Validate(true) on an empty SMTPConf should report errors")
+	}
+	if smtp.UseThisTime {
+		t.Error("Validate(true) must not mutate UseThisTime as a side effect")
+	}
+}
+
+func TestSMTPConfValidateNotRequiredSkipsWhenDisabled(t *testing.T) {
+	smtp := &SMTPConf{}
+
+	if errs := smtp.Validate(false); len(errs) != 0 {
+		t.Errorf("Validate(false) on a disabled SMTPConf should report no errors, got %v", errs)
+	}
+}
+
+func TestNotifierConfValidateSMTPDoesNotMutateSharedConf(t *testing.T) {
+	smtp := &SMTPConf{
+		SMTPAddr: "smtp.example.com",
+		SMTPPort: "587",
+		From:     "a@example.com",
+		To:       []string{"b@example.com"},
+	}
+	n := NotifierConf{Type: NotifierSMTP, SMTP: smtp}
+
+	if errs := n.Validate(); len(errs) != 0 {
+		t.Fatalf("Validate() on a well-formed smtp notifier returned errors: %v", errs)
+	}
+	if smtp.UseThisTime {
+		t.Error("NotifierConf.Validate must not mutate the shared SMTPConf's UseThisTime")
+	}
+}
+
+func TestNotifierConfValidateUnknownType(t *testing.T) {
+	n := NotifierConf{Type: "carrier-pigeon"}
+
+	errs := n.Validate()
+	if len(errs) != 1 {
+		t.Fatalf("Validate() on an unknown notifier type = %v, want exactly one error", errs)
+	}
+}
+
+func TestNotifierConfValidateWebhookRejectsMalformedURL(t *testing.T) {
+	n := NotifierConf{Type: NotifierWebhook, Webhook: &WebhookConf{URL: "not-a-url!!"}}
+
+	if errs := n.Validate(); len(errs) == 0 {
+		t.Error("Validate() on a webhook notifier with a malformed url should report an error")
+	}
+}
+
+func TestNotifierConfValidateWebhookAcceptsWellFormedURL(t *testing.T) {
+	n := NotifierConf{Type: NotifierWebhook, Webhook: &WebhookConf{URL: "https://example.com/hook"}}
+
+	if errs := n.Validate(); len(errs) != 0 {
+		t.Errorf("Validate() on a well-formed webhook notifier returned errors: %v", errs)
+	}
+}
+
+func TestNotifierConfValidateGitHubRequiresOwner(t *testing.T) {
+	n := NotifierConf{Type: NotifierGitHub, GitHub: &GitHubConf{Token: "tok", Repository: "repo"}}
+
+	if errs := n.Validate(); len(errs) == 0 {
+		t.Error("Validate() on a github notifier with no Owner should report an error")
+	}
+}
+
+func TestNotifierConfValidateGitHubAcceptsOwnerTokenAndRepository(t *testing.T) {
+	n := NotifierConf{Type: NotifierGitHub, GitHub: &GitHubConf{Owner: "me", Token: "tok", Repository: "repo"}}
+
+	if errs := n.Validate(); len(errs) != 0 {
+		t.Errorf("Validate() on a well-formed github notifier returned errors: %v", errs)
+	}
+}
+
+func TestIsValidFamily(t *testing.T) {
+	if !IsValidFamily(RedHat) {
+		t.Errorf("IsValidFamily(%q) = false, want true", RedHat)
+	}
+	if IsValidFamily("not-a-real-family") {
+		t.Error(`IsValidFamily("not-a-real-family") = true, want false`)
+	}
+}
+
+func TestServerInfoValidateRejectsUnknownFamily(t *testing.T) {
+	s := ServerInfo{Host: "example.com", Family: "not-a-real-family"}
+	errs := s.Validate()
+	if len(errs) != 1 {
+		t.Fatalf("Validate() on an unknown family = %v, want exactly one error", errs)
+	}
+}
+
+func TestServerInfoValidateAcceptsKnownFamily(t *testing.T) {
+	s := ServerInfo{Host: "example.com", Family: RedHat}
+	if errs := s.Validate(); len(errs) != 0 {
+		t.Errorf("Validate() with family %q = %v, want no errors", RedHat, errs)
+	}
+}
+
+func TestServerInfoValidatePseudoRequiresCpeNames(t *testing.T) {
+	s := ServerInfo{Type: ServerTypePseudo}
+	if errs := s.Validate(); len(errs) == 0 {
+		t.Error("Validate() on a pseudo server with no CpeNames should report an error")
+	}
+
+	s.CpeNames = []string{"cpe:/a:example:widget:1.0"}
+	if errs := s.Validate(); len(errs) != 0 {
+		t.Errorf("Validate() on a pseudo server with CpeNames set = %v, want no errors", errs)
+	}
+}
+
+func TestServerInfoValidatePseudoSkipsHostCheck(t *testing.T) {
+	s := ServerInfo{
+		Type:     ServerTypePseudo,
+		CpeNames: []string{"cpe:/a:example:widget:1.0"},
+	}
+	for _, err := range s.Validate() {
+		t.Errorf("pseudo server with no Host should not be validated on Host, got error: %v", err)
+	}
+}
+
+func TestServerInfoValidateNonPseudoRequiresHost(t *testing.T) {
+	s := ServerInfo{}
+	if errs := s.Validate(); len(errs) == 0 {
+		t.Error("Validate() on a non-pseudo server with no Host should report an error")
+	}
+}
+
+func TestSyslogConfValidateRejectsUnknownFacility(t *testing.T) {
+	c := &SyslogConf{Facility: "not-a-facility"}
+	if errs := c.Validate(); len(errs) == 0 {
+		t.Error("Validate() with an unknown facility should report an error")
+	}
+}
+
+func TestSyslogConfValidateRejectsUnknownSeverity(t *testing.T) {
+	c := &SyslogConf{Severity: "not-a-severity"}
+	if errs := c.Validate(); len(errs) == 0 {
+		t.Error("Validate() with an unknown severity should report an error")
+	}
+}
+
+func TestSyslogConfValidateAcceptsKnownFacilityAndSeverity(t *testing.T) {
+	c := &SyslogConf{Facility: "local0", Severity: "warning"}
+	if errs := c.Validate(); len(errs) != 0 {
+		t.Errorf("Validate() with a known facility/severity returned errors: %v", errs)
+	}
+}
+
+func TestLogConfValidateRejectsRelativeDir(t *testing.T) {
+	c := LogConf{Dir: "relative/path"}
+	if errs := c.Validate(); len(errs) == 0 {
+		t.Error("Validate() with a relative Log.Dir should report an error")
+	}
+}
+
+func TestLogConfValidateAcceptsAbsoluteDir(t *testing.T) {
+	c := LogConf{Dir: "/var/log/vuls"}
+	if errs := c.Validate(); len(errs) != 0 {
+		t.Errorf("Validate() with an absolute Log.Dir returned errors: %v", errs)
+	}
+}
@@ -0,0 +1,111 @@
+/* Vuls - Vulnerability Scanner
+Copyright (C) 2016  Future Architect, Inc. Japan.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package notify
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-github/github"
+	"golang.org/x/oauth2"
+
+	"github.com/future-architect/vuls/config"
+	"github.com/future-architect/vuls/report"
+)
+
+// githubNotifier opens or updates one GitHub Issue per CVE, titled with
+// the CVE ID so re-running a scan updates the existing issue instead of
+// creating a duplicate.
+type githubNotifier struct {
+	conf          config.GitHubConf
+	cvssScoreOver float64
+}
+
+func (n *githubNotifier) client(ctx context.Context) *github.Client {
+	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: n.conf.Token})
+	return github.NewClient(oauth2.NewClient(ctx, ts))
+}
+
+func (n *githubNotifier) Notify(ctx context.Context, r report.ScanReport) error {
+	cves := filterBySeverity(r, n.cvssScoreOver)
+	if len(cves) == 0 {
+		return nil
+	}
+
+	client := n.client(ctx)
+	for _, cve := range cves {
+		if err := n.openOrUpdateIssue(ctx, client, r, cve); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (n *githubNotifier) openOrUpdateIssue(ctx context.Context, client *github.Client, r report.ScanReport, cve report.CveInfo) error {
+	title := fmt.Sprintf("[vuls] %s", cve.CveID)
+	body := fmt.Sprintf("CVSS %.1f on %s\n\n%s", cve.CvssScore, r.ServerInfo.ServerName, cve.Summary)
+
+	existing, err := n.findIssueByTitle(ctx, client, title)
+	if err != nil {
+		return err
+	}
+
+	if existing != nil {
+		_, _, err := client.Issues.Edit(ctx, n.conf.Owner, n.conf.Repository, existing.GetNumber(), &github.IssueRequest{
+			Body: &body,
+		})
+		return err
+	}
+
+	_, _, err = client.Issues.Create(ctx, n.conf.Owner, n.conf.Repository, &github.IssueRequest{
+		Title: &title,
+		Body:  &body,
+	})
+	return err
+}
+
+// findIssueByTitle looks for an issue with an exact title match,
+// open or closed. GitHub's search API doesn't guarantee exact
+// substring matching even when the query is quoted, so a search-based
+// lookup can silently miss the prior issue and create a duplicate;
+// listing every issue in the repo and comparing titles exactly avoids
+// that.
+func (n *githubNotifier) findIssueByTitle(ctx context.Context, client *github.Client, title string) (*github.Issue, error) {
+	opts := &github.IssueListByRepoOptions{
+		State:       "all",
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+
+	for {
+		issues, resp, err := client.Issues.ListByRepo(ctx, n.conf.Owner, n.conf.Repository, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, issue := range issues {
+			if issue.GetTitle() == title {
+				return issue, nil
+			}
+		}
+
+		if resp.NextPage == 0 {
+			return nil, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
@@ -0,0 +1,100 @@
+/* Vuls - Vulnerability Scanner
+Copyright (C) 2016  Future Architect, Inc. Japan.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+// Package notify fans a ScanReport out to one or more configured
+// destinations (SMTP, Slack, generic webhook, GitHub Issues).
+package notify
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/future-architect/vuls/config"
+	"github.com/future-architect/vuls/report"
+)
+
+// Notifier delivers a ScanReport to a single destination.
+type Notifier interface {
+	Notify(ctx context.Context, r report.ScanReport) error
+}
+
+// NewNotifiers builds a Notifier for every entry in confs, in order.
+// Unknown notifier types are reported as an error rather than skipped
+// silently.
+func NewNotifiers(confs []config.NotifierConf) (notifiers []Notifier, err error) {
+	for _, c := range confs {
+		n, err := newNotifier(c)
+		if err != nil {
+			return nil, err
+		}
+		notifiers = append(notifiers, n)
+	}
+	return notifiers, nil
+}
+
+func newNotifier(c config.NotifierConf) (Notifier, error) {
+	switch c.Type {
+	case config.NotifierSMTP:
+		if c.SMTP == nil {
+			return nil, fmt.Errorf("smtp notifier requires an smtp config block")
+		}
+		return &smtpNotifier{conf: *c.SMTP, cvssScoreOver: c.CvssScoreOver}, nil
+	case config.NotifierSlack:
+		if c.Slack == nil {
+			return nil, fmt.Errorf("slack notifier requires a slack config block")
+		}
+		return &slackNotifier{conf: *c.Slack, cvssScoreOver: c.CvssScoreOver}, nil
+	case config.NotifierWebhook:
+		if c.Webhook == nil {
+			return nil, fmt.Errorf("webhook notifier requires a webhook config block")
+		}
+		return &webhookNotifier{conf: *c.Webhook, cvssScoreOver: c.CvssScoreOver}, nil
+	case config.NotifierGitHub:
+		if c.GitHub == nil {
+			return nil, fmt.Errorf("github notifier requires a github config block")
+		}
+		return &githubNotifier{conf: *c.GitHub, cvssScoreOver: c.CvssScoreOver}, nil
+	default:
+		return nil, fmt.Errorf("Unknown notifier type: %s", c.Type)
+	}
+}
+
+// filterBySeverity drops CveInfo entries below the notifier's configured
+// CvssScoreOver threshold.
+func filterBySeverity(r report.ScanReport, cvssScoreOver float64) []report.CveInfo {
+	if cvssScoreOver <= 0 {
+		return r.ScannedCves
+	}
+	filtered := make([]report.CveInfo, 0, len(r.ScannedCves))
+	for _, cve := range r.ScannedCves {
+		if cve.CvssScore >= cvssScoreOver {
+			filtered = append(filtered, cve)
+		}
+	}
+	return filtered
+}
+
+// FanOut sends r to every notifier, continuing past individual
+// failures and returning every error, one per failed notifier.
+func FanOut(ctx context.Context, notifiers []Notifier, r report.ScanReport) (errs []error) {
+	for _, n := range notifiers {
+		if err := n.Notify(ctx, r); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return
+}
@@ -0,0 +1,88 @@
+/* Vuls - Vulnerability Scanner
+Copyright (C) 2016  Future Architect, Inc. Japan.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package notify
+
+import (
+	"testing"
+
+	"github.com/future-architect/vuls/config"
+	"github.com/future-architect/vuls/report"
+)
+
+func TestFilterBySeverity(t *testing.T) {
+	r := report.ScanReport{
+		ScannedCves: []report.CveInfo{
+			{CveID: "CVE-2020-0001", CvssScore: 3.0},
+			{CveID: "CVE-2020-0002", CvssScore: 7.0},
+			{CveID: "CVE-2020-0003", CvssScore: 7.5},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		cvssScoreOver float64
+		want          []string
+	}{
+		{"zero threshold passes everything through unfiltered", 0, []string{"CVE-2020-0001", "CVE-2020-0002", "CVE-2020-0003"}},
+		{"negative threshold passes everything through unfiltered", -1, []string{"CVE-2020-0001", "CVE-2020-0002", "CVE-2020-0003"}},
+		{"threshold excludes strictly-below scores", 7.0, []string{"CVE-2020-0002", "CVE-2020-0003"}},
+		{"threshold above every score excludes all", 9.0, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := filterBySeverity(r, tt.cvssScoreOver)
+			if len(got) != len(tt.want) {
+				t.Fatalf("filterBySeverity(%v) = %v, want %v", tt.cvssScoreOver, ids(got), tt.want)
+			}
+			for i, cve := range got {
+				if cve.CveID != tt.want[i] {
+					t.Errorf("filterBySeverity(%v)[%d] = %s, want %s", tt.cvssScoreOver, i, cve.CveID, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestNewNotifierRejectsMissingConfigBlock(t *testing.T) {
+	tests := []struct {
+		name string
+		conf config.NotifierConf
+	}{
+		{"smtp type with nil SMTP block", config.NotifierConf{Type: config.NotifierSMTP}},
+		{"slack type with nil Slack block", config.NotifierConf{Type: config.NotifierSlack}},
+		{"webhook type with nil Webhook block", config.NotifierConf{Type: config.NotifierWebhook}},
+		{"github type with nil GitHub block", config.NotifierConf{Type: config.NotifierGitHub}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := newNotifier(tt.conf); err == nil {
+				t.Errorf("newNotifier(%+v) = nil error, want an error instead of a nil-pointer panic", tt.conf)
+			}
+		})
+	}
+}
+
+func ids(cves []report.CveInfo) []string {
+	out := make([]string, len(cves))
+	for i, c := range cves {
+		out[i] = c.CveID
+	}
+	return out
+}
@@ -0,0 +1,83 @@
+/* Vuls - Vulnerability Scanner
+Copyright (C) 2016  Future Architect, Inc. Japan.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/future-architect/vuls/config"
+	"github.com/future-architect/vuls/report"
+)
+
+type slackNotifier struct {
+	conf          config.SlackConf
+	cvssScoreOver float64
+}
+
+type slackPayload struct {
+	Channel   string `json:"channel"`
+	IconEmoji string `json:"icon_emoji"`
+	Username  string `json:"username"`
+	Text      string `json:"text"`
+}
+
+func (n *slackNotifier) Notify(ctx context.Context, r report.ScanReport) error {
+	cves := filterBySeverity(r, n.cvssScoreOver)
+	if len(cves) == 0 {
+		return nil
+	}
+
+	text := fmt.Sprintf("*%s*: %d CVEs found\n", r.ServerInfo.ServerName, len(cves))
+	for _, cve := range cves {
+		text += fmt.Sprintf("%s (CVSS %.1f): %s\n", cve.CveID, cve.CvssScore, cve.Summary)
+	}
+
+	payload := slackPayload{
+		Channel:   n.conf.Channel,
+		IconEmoji: n.conf.IconEmoji,
+		Username:  n.conf.AuthUser,
+		Text:      text,
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", n.conf.HookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("slack notify failed: %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+/* Vuls - Vulnerability Scanner
+Copyright (C) 2016  Future Architect, Inc. Japan.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package notify
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/smtp"
+	"strings"
+
+	"github.com/future-architect/vuls/config"
+	"github.com/future-architect/vuls/report"
+)
+
+type smtpNotifier struct {
+	conf          config.SMTPConf
+	cvssScoreOver float64
+}
+
+func (n *smtpNotifier) Notify(ctx context.Context, r report.ScanReport) error {
+	cves := filterBySeverity(r, n.cvssScoreOver)
+	if len(cves) == 0 {
+		return nil
+	}
+
+	auth, err := n.auth()
+	if err != nil {
+		return err
+	}
+
+	msg := n.buildMessage(r, cves)
+	addr := fmt.Sprintf("%s:%s", n.conf.SMTPAddr, n.conf.SMTPPort)
+	to := append(append([]string{}, n.conf.To...), n.conf.Cc...)
+	return smtp.SendMail(addr, auth, n.conf.From, to, msg)
+}
+
+// auth picks the smtp.Auth implementation matching conf.AuthMechanism.
+// LOGIN isn't provided by net/smtp, so it's implemented here directly
+// since Office365 and some older appliances require it.
+func (n *smtpNotifier) auth() (smtp.Auth, error) {
+	host := strings.Split(n.conf.SMTPAddr, ":")[0]
+	switch n.conf.AuthMechanism {
+	case "", config.SMTPAuthPlain:
+		return smtp.PlainAuth("", n.conf.User, n.conf.Password, host), nil
+	case config.SMTPAuthLogin:
+		return &loginAuth{username: n.conf.User, password: n.conf.Password}, nil
+	case config.SMTPAuthCRAMMD5:
+		return smtp.CRAMMD5Auth(n.conf.User, n.conf.Password), nil
+	default:
+		return nil, fmt.Errorf("Unknown SMTP auth mechanism: %s", n.conf.AuthMechanism)
+	}
+}
+
+func (n *smtpNotifier) buildMessage(r report.ScanReport, cves []report.CveInfo) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", n.conf.From)
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(n.conf.To, ", "))
+	if len(n.conf.Cc) > 0 {
+		fmt.Fprintf(&buf, "Cc: %s\r\n", strings.Join(n.conf.Cc, ", "))
+	}
+	fmt.Fprintf(&buf, "Subject: %s%s: %d CVEs found\r\n\r\n",
+		n.conf.SubjectPrefix, r.ServerInfo.ServerName, len(cves))
+	for _, cve := range cves {
+		fmt.Fprintf(&buf, "%s (CVSS %.1f): %s\n", cve.CveID, cve.CvssScore, cve.Summary)
+	}
+	return buf.Bytes()
+}
+
+// loginAuth implements the SMTP LOGIN auth mechanism, which isn't part
+// of net/smtp but is required by Office365 and some older appliances.
+type loginAuth struct {
+	username string
+	password string
+}
+
+func (a *loginAuth) Start(server *smtp.ServerInfo) (string, []byte, error) {
+	return "LOGIN", []byte{}, nil
+}
+
+func (a *loginAuth) Next(fromServer []byte, more bool) ([]byte, error) {
+	if !more {
+		return nil, nil
+	}
+	switch strings.ToLower(string(fromServer)) {
+	case "username:":
+		return []byte(a.username), nil
+	case "password:":
+		return []byte(a.password), nil
+	default:
+		return nil, errors.New("Unknown LOGIN auth prompt from server")
+	}
+}
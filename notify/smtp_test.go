@@ -0,0 +1,65 @@
+/* Vuls - Vulnerability Scanner
+Copyright (C) 2016  Future Architect, Inc. Japan.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package notify
+
+import "testing"
+
+func TestLoginAuthStart(t *testing.T) {
+	a := &loginAuth{username: "user", password: "pass"}
+
+	proto, toServer, err := a.Start(nil)
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if proto != "LOGIN" {
+		t.Errorf("Start() proto = %q, want %q", proto, "LOGIN")
+	}
+	if len(toServer) != 0 {
+		t.Errorf("Start() toServer = %q, want empty", toServer)
+	}
+}
+
+func TestLoginAuthNext(t *testing.T) {
+	a := &loginAuth{username: "user", password: "pass"}
+
+	tests := []struct {
+		name       string
+		fromServer string
+		more       bool
+		want       string
+		wantErr    bool
+	}{
+		{"username prompt", "Username:", true, "user", false},
+		{"password prompt", "Password:", true, "pass", false},
+		{"prompt casing is ignored", "USERNAME:", true, "user", false},
+		{"no more input ends the exchange", "", false, "", false},
+		{"unknown prompt is rejected", "Quux:", true, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := a.Next([]byte(tt.fromServer), tt.more)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Next(%q, %v) error = %v, wantErr %v", tt.fromServer, tt.more, err, tt.wantErr)
+			}
+			if err == nil && string(got) != tt.want {
+				t.Errorf("Next(%q, %v) = %q, want %q", tt.fromServer, tt.more, got, tt.want)
+			}
+		})
+	}
+}
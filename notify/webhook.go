@@ -0,0 +1,70 @@
+/* Vuls - Vulnerability Scanner
+Copyright (C) 2016  Future Architect, Inc. Japan.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/future-architect/vuls/config"
+	"github.com/future-architect/vuls/report"
+)
+
+type webhookNotifier struct {
+	conf          config.WebhookConf
+	cvssScoreOver float64
+}
+
+func (n *webhookNotifier) Notify(ctx context.Context, r report.ScanReport) error {
+	cves := filterBySeverity(r, n.cvssScoreOver)
+	if len(cves) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(struct {
+		ServerName string           `json:"server_name"`
+		Cves       []report.CveInfo `json:"cves"`
+	}{
+		ServerName: r.ServerInfo.ServerName,
+		Cves:       cves,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", n.conf.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || 300 <= resp.StatusCode {
+		return fmt.Errorf("webhook notify failed: %s", resp.Status)
+	}
+	return nil
+}
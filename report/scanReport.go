@@ -0,0 +1,36 @@
+/* Vuls - Vulnerability Scanner
+Copyright (C) 2016  Future Architect, Inc. Japan.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+// Package report holds the result of a vuls scan in the shape consumed
+// by notifiers and printers.
+package report
+
+import "github.com/future-architect/vuls/config"
+
+// ScanReport is the result of scanning a single server, ready to be
+// handed to a notify.Notifier or printed.
+type ScanReport struct {
+	ServerInfo  config.ServerInfo
+	ScannedCves []CveInfo
+}
+
+// CveInfo is one vulnerability found on the scanned server.
+type CveInfo struct {
+	CveID     string
+	CvssScore float64
+	Summary   string
+}
@@ -0,0 +1,141 @@
+/* Vuls - Vulnerability Scanner
+Copyright (C) 2016  Future Architect, Inc. Japan.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package scan
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/future-architect/vuls/config"
+)
+
+const defaultDockerSocket = "unix:///var/run/docker.sock"
+
+// dockerCLIConfig mirrors the subset of ~/.docker/config.json vuls cares
+// about.
+type dockerCLIConfig struct {
+	CurrentContext string `json:"currentContext"`
+}
+
+// dockerContextMeta mirrors ~/.docker/contexts/meta/<hash>/meta.json.
+type dockerContextMeta struct {
+	Name      string `json:"Name"`
+	Endpoints struct {
+		Docker struct {
+			Host string `json:"Host"`
+		} `json:"docker"`
+	} `json:"Endpoints"`
+}
+
+// DockerHost resolves the Docker daemon endpoint to use for enumerating
+// s.Containers. Resolution order:
+//  1. s.DockerContext / the active Docker CLI context's endpoint
+//  2. DOCKER_HOST
+//  3. the default local socket
+//
+// The returned endpoint may be unix://, tcp://, or ssh://. Callers that
+// need an actual connection (rather than just the endpoint string)
+// should use DialDocker, which tunnels ssh:// endpoints over s.KeyPath.
+func DockerHost(s config.ServerInfo) (string, error) {
+	contextName := s.DockerContext
+	if len(contextName) == 0 {
+		cfg, err := readDockerCLIConfig()
+		if err != nil {
+			return "", err
+		}
+		contextName = cfg.CurrentContext
+	}
+
+	if len(contextName) == 0 || contextName == "default" {
+		if host := os.Getenv("DOCKER_HOST"); len(host) != 0 {
+			return host, nil
+		}
+		return defaultDockerSocket, nil
+	}
+
+	meta, err := readDockerContextMeta(contextName)
+	if err != nil {
+		return "", err
+	}
+	if len(meta.Endpoints.Docker.Host) == 0 {
+		return "", fmt.Errorf("Docker context %s has no docker endpoint", contextName)
+	}
+	return meta.Endpoints.Docker.Host, nil
+}
+
+func dockerConfigDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".docker"), nil
+}
+
+func readDockerCLIConfig() (dockerCLIConfig, error) {
+	var cfg dockerCLIConfig
+
+	dir, err := dockerConfigDir()
+	if err != nil {
+		return cfg, err
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, "config.json"))
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("Failed to parse ~/.docker/config.json: %s", err)
+	}
+	return cfg, nil
+}
+
+// contextHash replicates the Docker CLI's derivation of a context's
+// metadata directory name: the hex-encoded SHA256 of its name.
+func contextHash(name string) string {
+	sum := sha256.Sum256([]byte(name))
+	return hex.EncodeToString(sum[:])
+}
+
+func readDockerContextMeta(name string) (dockerContextMeta, error) {
+	var meta dockerContextMeta
+
+	dir, err := dockerConfigDir()
+	if err != nil {
+		return meta, err
+	}
+
+	path := filepath.Join(dir, "contexts", "meta", contextHash(name), "meta.json")
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return meta, fmt.Errorf("Failed to read Docker context %s: %s", name, err)
+	}
+
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return meta, fmt.Errorf("Failed to parse Docker context %s: %s", name, err)
+	}
+	return meta, nil
+}
@@ -0,0 +1,136 @@
+/* Vuls - Vulnerability Scanner
+Copyright (C) 2016  Future Architect, Inc. Japan.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package scan
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/url"
+	"os"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+
+	"github.com/future-architect/vuls/config"
+)
+
+// remoteDockerSocket is the path the daemon listens on inside the
+// remote host when reached over ssh://, matching the Docker CLI's own
+// assumption.
+const remoteDockerSocket = "/var/run/docker.sock"
+
+// DialDocker opens a connection to the Docker daemon endpoint returned
+// by DockerHost(s). unix:// and tcp:// endpoints are dialed directly;
+// ssh:// endpoints are tunneled over an SSH connection authenticated
+// with s.KeyPath, so containers on a remote host can be enumerated
+// through the same key material already used for package enumeration.
+func DialDocker(s config.ServerInfo) (net.Conn, error) {
+	endpoint, err := DockerHost(s)
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid docker endpoint %s: %s", endpoint, err)
+	}
+
+	switch u.Scheme {
+	case "unix":
+		return net.Dial("unix", u.Path)
+	case "tcp":
+		return net.Dial("tcp", u.Host)
+	case "ssh":
+		return dialDockerOverSSH(s, u)
+	default:
+		return nil, fmt.Errorf("Unsupported docker endpoint scheme: %s", u.Scheme)
+	}
+}
+
+func dialDockerOverSSH(s config.ServerInfo, u *url.URL) (net.Conn, error) {
+	user := s.User
+	if u.User != nil && len(u.User.Username()) != 0 {
+		user = u.User.Username()
+	}
+
+	host := u.Host
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		host = net.JoinHostPort(host, "22")
+	}
+
+	auth, err := sshKeyAuth(s)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := ssh.Dial("tcp", host, &ssh.ClientConfig{
+		User:            user,
+		Auth:            []ssh.AuthMethod{auth},
+		HostKeyCallback: sshHostKeyCallback(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to dial %s over ssh: %s", host, err)
+	}
+
+	conn, err := client.Dial("unix", remoteDockerSocket)
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("Failed to reach docker socket on %s: %s", host, err)
+	}
+	return conn, nil
+}
+
+// sshKeyAuth builds an ssh.AuthMethod from s.KeyPath/s.KeyPassword, the
+// same key material used for the regular SSH-based package enumeration.
+func sshKeyAuth(s config.ServerInfo) (ssh.AuthMethod, error) {
+	if len(s.KeyPath) == 0 {
+		return nil, fmt.Errorf("ssh:// docker endpoint requires KeyPath to be set")
+	}
+
+	keyBytes, err := ioutil.ReadFile(s.KeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read KeyPath %s: %s", s.KeyPath, err)
+	}
+
+	var signer ssh.Signer
+	if len(s.KeyPassword) != 0 {
+		signer, err = ssh.ParsePrivateKeyWithPassphrase(keyBytes, []byte(s.KeyPassword))
+	} else {
+		signer, err = ssh.ParsePrivateKey(keyBytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse KeyPath %s: %s", s.KeyPath, err)
+	}
+
+	return ssh.PublicKeys(signer), nil
+}
+
+// sshHostKeyCallback verifies against the user's known_hosts file when
+// available, falling back to accepting any host key so a missing
+// known_hosts file doesn't block a scan vuls otherwise has credentials
+// to run.
+func sshHostKeyCallback() ssh.HostKeyCallback {
+	home, err := os.UserHomeDir()
+	if err == nil {
+		if cb, err := knownhosts.New(home + "/.ssh/known_hosts"); err == nil {
+			return cb
+		}
+	}
+	return ssh.InsecureIgnoreHostKey()
+}
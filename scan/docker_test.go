@@ -0,0 +1,149 @@
+/* Vuls - Vulnerability Scanner
+Copyright (C) 2016  Future Architect, Inc. Japan.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package scan
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/future-architect/vuls/config"
+)
+
+// contextHash is deterministic, so any change in its output changes
+// which meta.json every existing Docker CLI install is read from.
+func TestContextHash(t *testing.T) {
+	got := contextHash("my-context")
+	if len(got) != 64 {
+		t.Fatalf("contextHash() = %q, want a 64-char hex string", got)
+	}
+	if got == contextHash("other-context") {
+		t.Error("contextHash() produced the same hash for two different context names")
+	}
+	if got != contextHash("my-context") {
+		t.Error("contextHash() is not deterministic")
+	}
+}
+
+func withHome(t *testing.T) string {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "vuls-docker-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	oldHome := os.Getenv("HOME")
+	os.Setenv("HOME", dir)
+	t.Cleanup(func() { os.Setenv("HOME", oldHome) })
+
+	return dir
+}
+
+func TestDockerHostDefaultsToLocalSocketWithNoConfig(t *testing.T) {
+	withHome(t)
+	os.Unsetenv("DOCKER_HOST")
+
+	got, err := DockerHost(config.ServerInfo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != defaultDockerSocket {
+		t.Errorf("DockerHost() = %q, want %q", got, defaultDockerSocket)
+	}
+}
+
+func TestDockerHostFallsBackToDockerHostEnv(t *testing.T) {
+	withHome(t)
+	os.Setenv("DOCKER_HOST", "tcp://192.0.2.1:2375")
+	defer os.Unsetenv("DOCKER_HOST")
+
+	got, err := DockerHost(config.ServerInfo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "tcp://192.0.2.1:2375" {
+		t.Errorf("DockerHost() = %q, want %q", got, "tcp://192.0.2.1:2375")
+	}
+}
+
+func TestDockerHostReadsActiveCLIContext(t *testing.T) {
+	home := withHome(t)
+	os.Unsetenv("DOCKER_HOST")
+
+	writeJSON(t, filepath.Join(home, ".docker", "config.json"), dockerCLIConfig{
+		CurrentContext: "remote-box",
+	})
+
+	metaDir := filepath.Join(home, ".docker", "contexts", "meta", contextHash("remote-box"))
+	if err := os.MkdirAll(metaDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	meta := dockerContextMeta{Name: "remote-box"}
+	meta.Endpoints.Docker.Host = "ssh://user@remote-box"
+	writeJSON(t, filepath.Join(metaDir, "meta.json"), meta)
+
+	got, err := DockerHost(config.ServerInfo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "ssh://user@remote-box" {
+		t.Errorf("DockerHost() = %q, want %q", got, "ssh://user@remote-box")
+	}
+}
+
+func TestDockerHostServerOverridesContext(t *testing.T) {
+	home := withHome(t)
+	os.Unsetenv("DOCKER_HOST")
+
+	writeJSON(t, filepath.Join(home, ".docker", "config.json"), dockerCLIConfig{
+		CurrentContext: "default",
+	})
+
+	metaDir := filepath.Join(home, ".docker", "contexts", "meta", contextHash("from-server-info"))
+	if err := os.MkdirAll(metaDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	meta := dockerContextMeta{Name: "from-server-info"}
+	meta.Endpoints.Docker.Host = "tcp://10.0.0.1:2375"
+	writeJSON(t, filepath.Join(metaDir, "meta.json"), meta)
+
+	got, err := DockerHost(config.ServerInfo{DockerContext: "from-server-info"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "tcp://10.0.0.1:2375" {
+		t.Errorf("DockerHost() = %q, want %q", got, "tcp://10.0.0.1:2375")
+	}
+}
+
+func writeJSON(t *testing.T, path string, v interface{}) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+}
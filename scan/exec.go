@@ -0,0 +1,115 @@
+/* Vuls - Vulnerability Scanner
+Copyright (C) 2016  Future Architect, Inc. Japan.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package scan
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/future-architect/vuls/config"
+)
+
+// runCommand runs cmd on s and returns its stdout as is. Local hosts
+// (ServerInfo.IsLocal) run the command directly; remote hosts are
+// reached over the `ssh` binary using the same User/Host/Port/KeyPath
+// already configured on ServerInfo.
+func runCommand(s config.ServerInfo, cmd string) (string, error) {
+	return runCommandAllowExit(s, cmd)
+}
+
+// runCommandAllowExit is like runCommand but treats the listed exit
+// codes as success. Some package managers (yum check-update, for
+// instance) use a non-zero exit code to mean "there is output" rather
+// than "this failed".
+func runCommandAllowExit(s config.ServerInfo, cmd string, allowedExitCodes ...int) (string, error) {
+	c := buildCommand(s, cmd)
+
+	var stdout, stderr bytes.Buffer
+	c.Stdout = &stdout
+	c.Stderr = &stderr
+
+	err := c.Run()
+	if err != nil {
+		exitErr, ok := err.(*exec.ExitError)
+		if !ok || !containsInt(allowedExitCodes, exitErr.ExitCode()) {
+			return "", fmt.Errorf("%s: %s: %s", cmd, err, stderr.String())
+		}
+	}
+	return stdout.String(), nil
+}
+
+func buildCommand(s config.ServerInfo, cmd string) *exec.Cmd {
+	if s.IsLocal() {
+		return exec.Command("sh", "-c", cmd)
+	}
+
+	args := sshHostKeyArgs()
+	if len(s.Port) != 0 {
+		args = append(args, "-p", s.Port)
+	}
+	if len(s.KeyPath) != 0 {
+		args = append(args, "-i", s.KeyPath)
+	}
+
+	user := s.User
+	if len(user) == 0 {
+		user = "root"
+	}
+	args = append(args, fmt.Sprintf("%s@%s", user, s.Host), cmd)
+	return exec.Command("ssh", args...)
+}
+
+// sshHostKeyArgs returns the `ssh` flags used to verify the remote
+// host key. When the user has a known_hosts file, it's used to verify
+// as usual; only when one can't be found do we fall back to disabling
+// strict checking, matching the best-effort verification DialDocker's
+// sshHostKeyCallback does for ssh:// docker endpoints.
+func sshHostKeyArgs() []string {
+	home, err := os.UserHomeDir()
+	if err == nil {
+		knownHosts := home + "/.ssh/known_hosts"
+		if _, err := os.Stat(knownHosts); err == nil {
+			return []string{"-o", "StrictHostKeyChecking=yes", "-o", "UserKnownHostsFile=" + knownHosts}
+		}
+	}
+	return []string{"-o", "StrictHostKeyChecking=no"}
+}
+
+func containsInt(list []int, n int) bool {
+	for _, v := range list {
+		if v == n {
+			return true
+		}
+	}
+	return false
+}
+
+// splitLines splits command output into non-empty, trimmed lines.
+func splitLines(out string) []string {
+	var lines []string
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimSpace(line)
+		if len(line) != 0 {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
@@ -0,0 +1,352 @@
+/* Vuls - Vulnerability Scanner
+Copyright (C) 2016  Future Architect, Inc. Japan.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package scan
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/future-architect/vuls/config"
+)
+
+// OSFamily is the per-distro scanning backend. Each implementation knows
+// how to enumerate installed and updatable packages with the package
+// manager used by its family; CVE lookups are not done here.
+type OSFamily interface {
+	// InstalledPackages lists the packages installed on the target.
+	InstalledPackages() ([]string, error)
+
+	// ScanUpdatablePackages lists packages that have a newer version
+	// available via the family's package manager.
+	ScanUpdatablePackages() ([]string, error)
+}
+
+// NewOSFamily returns the OSFamily implementation for the given
+// config.ServerInfo, selected off ServerInfo.Family.
+func NewOSFamily(c config.ServerInfo) (OSFamily, error) {
+	switch c.Family {
+	case config.RedHat, config.CentOS, config.Amazon, config.Oracle:
+		return &rpmFamily{serverInfo: c}, nil
+	case config.Debian, config.Ubuntu, config.Raspbian:
+		return &debianFamily{serverInfo: c}, nil
+	case config.FreeBSD:
+		return &freebsdFamily{serverInfo: c}, nil
+	case config.Alpine:
+		return &alpineFamily{serverInfo: c}, nil
+	case config.OpenSUSE, config.SUSEEnterpriseServer:
+		return &suseFamily{serverInfo: c}, nil
+	case config.Windows:
+		return &windowsFamily{serverInfo: c}, nil
+	default:
+		return nil, fmt.Errorf("Unsupported family: %s", c.Family)
+	}
+}
+
+// rpmFamily scans RedHat-based distros (RHEL, CentOS, Amazon, Oracle)
+// using rpm/yum.
+type rpmFamily struct {
+	serverInfo config.ServerInfo
+}
+
+// InstalledPackages lists packages via `rpm -qa`.
+func (f *rpmFamily) InstalledPackages() ([]string, error) {
+	out, err := runCommand(f.serverInfo, "rpm -qa --queryformat '%{NAME}-%{VERSION}-%{RELEASE}.%{ARCH}\\n'")
+	if err != nil {
+		return nil, err
+	}
+	return parseRPMQa(out), nil
+}
+
+// parseRPMQa parses `rpm -qa --queryformat '%{NAME}-%{VERSION}-%{RELEASE}.%{ARCH}\n'`
+// output: one already fully-formed NAME-VERSION-RELEASE.ARCH string per
+// line, nothing further to extract.
+func parseRPMQa(out string) []string {
+	return splitLines(out)
+}
+
+// ScanUpdatablePackages lists packages with updates via `yum check-update`.
+// yum exits 100 when updates are available, which runCommandAllowExit
+// treats as success rather than a failure.
+func (f *rpmFamily) ScanUpdatablePackages() ([]string, error) {
+	out, err := runCommandAllowExit(f.serverInfo, "yum check-update", 100)
+	if err != nil {
+		return nil, err
+	}
+	return parseYumCheckUpdate(out), nil
+}
+
+// parseYumCheckUpdate parses `yum check-update` output: after the
+// plugin/mirror header and the first blank line, one
+// "name.arch version repo" line per updatable package. Parsing stops
+// at the "Obsoleting Packages" banner; the entries below it are not
+// returned.
+func parseYumCheckUpdate(out string) []string {
+	var packages []string
+	inPackageList := false
+	for _, line := range strings.Split(out, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if len(trimmed) == 0 {
+			inPackageList = true
+			continue
+		}
+		if !inPackageList {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "Obsoleting Packages") {
+			break
+		}
+		fields := strings.Fields(trimmed)
+		if len(fields) == 3 {
+			packages = append(packages, fields[0])
+		}
+	}
+	return packages
+}
+
+// debianFamily scans Debian-based distros (Debian, Ubuntu, Raspbian)
+// using dpkg/apt.
+type debianFamily struct {
+	serverInfo config.ServerInfo
+}
+
+// InstalledPackages lists packages via `dpkg-query`.
+func (f *debianFamily) InstalledPackages() ([]string, error) {
+	out, err := runCommand(f.serverInfo, `dpkg-query -W -f='${Package}-${Version}\n'`)
+	if err != nil {
+		return nil, err
+	}
+	return parseDpkgQueryW(out), nil
+}
+
+// parseDpkgQueryW parses `dpkg-query -W -f='${Package}-${Version}\n'`
+// output: one already fully-formed NAME-VERSION string per line.
+func parseDpkgQueryW(out string) []string {
+	return splitLines(out)
+}
+
+// ScanUpdatablePackages lists packages with updates via
+// `apt-get upgrade --dry-run`.
+func (f *debianFamily) ScanUpdatablePackages() ([]string, error) {
+	out, err := runCommand(f.serverInfo, "apt-get upgrade --dry-run")
+	if err != nil {
+		return nil, err
+	}
+	return parseAptGetUpgradeDryRun(out), nil
+}
+
+// parseAptGetUpgradeDryRun parses `apt-get upgrade --dry-run` output:
+// package names are only on its "Inst <name> [<old>] (<new> ...)" lines.
+func parseAptGetUpgradeDryRun(out string) []string {
+	var packages []string
+	for _, line := range splitLines(out) {
+		if !strings.HasPrefix(line, "Inst ") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) >= 2 {
+			packages = append(packages, fields[1])
+		}
+	}
+	return packages
+}
+
+// freebsdFamily scans FreeBSD using pkg.
+type freebsdFamily struct {
+	serverInfo config.ServerInfo
+}
+
+// InstalledPackages lists packages via `pkg query`.
+func (f *freebsdFamily) InstalledPackages() ([]string, error) {
+	out, err := runCommand(f.serverInfo, "pkg query '%n-%v'")
+	if err != nil {
+		return nil, err
+	}
+	return parsePkgQuery(out), nil
+}
+
+// parsePkgQuery parses `pkg query '%n-%v'` output: one already
+// fully-formed NAME-VERSION string per line.
+func parsePkgQuery(out string) []string {
+	return splitLines(out)
+}
+
+// ScanUpdatablePackages lists packages with updates via
+// `pkg upgrade --dry-run`. pkg exits 0 whether or not there's anything
+// to upgrade, so no special exit-code handling is needed here.
+func (f *freebsdFamily) ScanUpdatablePackages() ([]string, error) {
+	out, err := runCommand(f.serverInfo, "pkg upgrade --dry-run --quiet")
+	if err != nil {
+		return nil, err
+	}
+	return parsePkgUpgradeDryRun(out), nil
+}
+
+// parsePkgUpgradeDryRun parses `pkg upgrade --dry-run --quiet` output.
+// `pkg upgrade -n` is narrative, not tabular: it prints a summary
+// banner, then an "Installed packages to be UPGRADED:" header followed
+// by one tab-indented "name: old -> new" line per package, then a
+// trailing summary. Only that block holds package names.
+func parsePkgUpgradeDryRun(out string) []string {
+	var packages []string
+	inUpgradeBlock := false
+	for _, line := range strings.Split(out, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "Installed packages to be UPGRADED:" {
+			inUpgradeBlock = true
+			continue
+		}
+		if !inUpgradeBlock {
+			continue
+		}
+		if len(trimmed) == 0 || !strings.Contains(trimmed, "->") {
+			break
+		}
+		name := strings.TrimSpace(strings.SplitN(trimmed, ":", 2)[0])
+		if len(name) != 0 {
+			packages = append(packages, name)
+		}
+	}
+	return packages
+}
+
+// alpineFamily scans Alpine Linux using apk.
+type alpineFamily struct {
+	serverInfo config.ServerInfo
+}
+
+// InstalledPackages lists packages via `apk info -v`.
+func (f *alpineFamily) InstalledPackages() ([]string, error) {
+	out, err := runCommand(f.serverInfo, "apk info -v")
+	if err != nil {
+		return nil, err
+	}
+	return parseApkInfoV(out), nil
+}
+
+// parseApkInfoV parses `apk info -v` output: one already fully-formed
+// NAME-VERSION string per line.
+func parseApkInfoV(out string) []string {
+	return splitLines(out)
+}
+
+// ScanUpdatablePackages lists packages with updates via
+// `apk version -l '<'`.
+func (f *alpineFamily) ScanUpdatablePackages() ([]string, error) {
+	out, err := runCommand(f.serverInfo, "apk version -l '<'")
+	if err != nil {
+		return nil, err
+	}
+	return parseApkVersionList(out), nil
+}
+
+// parseApkVersionList parses `apk version -l '<'` output: a header
+// line ("Installed:") followed by one "name-version<version" entry
+// per line.
+func parseApkVersionList(out string) []string {
+	var packages []string
+	for _, line := range splitLines(out) {
+		if strings.HasSuffix(line, ":") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) > 0 {
+			packages = append(packages, fields[0])
+		}
+	}
+	return packages
+}
+
+// suseFamily scans openSUSE and SUSE Enterprise Server using zypper.
+type suseFamily struct {
+	serverInfo config.ServerInfo
+}
+
+// InstalledPackages lists packages via `rpm -qa`.
+func (f *suseFamily) InstalledPackages() ([]string, error) {
+	out, err := runCommand(f.serverInfo, "rpm -qa --queryformat '%{NAME}-%{VERSION}-%{RELEASE}.%{ARCH}\\n'")
+	if err != nil {
+		return nil, err
+	}
+	return parseRPMQa(out), nil
+}
+
+// ScanUpdatablePackages lists packages with updates via
+// `zypper list-updates`.
+func (f *suseFamily) ScanUpdatablePackages() ([]string, error) {
+	out, err := runCommand(f.serverInfo, "zypper --non-interactive --quiet list-updates")
+	if err != nil {
+		return nil, err
+	}
+	return parseZypperListUpdates(out), nil
+}
+
+// parseZypperListUpdates parses `zypper list-updates` output: its
+// `|`-delimited table rows are prefixed with "v |" for an available
+// update, with the package name in the third column.
+func parseZypperListUpdates(out string) []string {
+	var packages []string
+	for _, line := range splitLines(out) {
+		if !strings.HasPrefix(line, "v |") {
+			continue
+		}
+		fields := strings.Split(line, "|")
+		if len(fields) >= 3 {
+			packages = append(packages, strings.TrimSpace(fields[2]))
+		}
+	}
+	return packages
+}
+
+// windowsFamily scans Windows hosts reachable via OpenSSH using
+// PowerShell's package management cmdlets.
+type windowsFamily struct {
+	serverInfo config.ServerInfo
+}
+
+// InstalledPackages lists packages via `Get-Package`.
+func (f *windowsFamily) InstalledPackages() ([]string, error) {
+	out, err := runCommand(f.serverInfo,
+		`powershell -NoProfile -Command "Get-Package | ForEach-Object { \"$($_.Name)-$($_.Version)\" }"`)
+	if err != nil {
+		return nil, err
+	}
+	return parseGetPackage(out), nil
+}
+
+// parseGetPackage parses the `Get-Package` PowerShell one-liner's
+// output: one already fully-formed NAME-VERSION string per line.
+func parseGetPackage(out string) []string {
+	return splitLines(out)
+}
+
+// ScanUpdatablePackages lists packages with updates via the PSWindowsUpdate
+// module's `Get-WindowsUpdate`.
+func (f *windowsFamily) ScanUpdatablePackages() ([]string, error) {
+	out, err := runCommand(f.serverInfo,
+		`powershell -NoProfile -Command "Get-WindowsUpdate | ForEach-Object { $_.KB }"`)
+	if err != nil {
+		return nil, err
+	}
+	return parseGetWindowsUpdate(out), nil
+}
+
+// parseGetWindowsUpdate parses the `Get-WindowsUpdate` PowerShell
+// one-liner's output: one KB identifier per line.
+func parseGetWindowsUpdate(out string) []string {
+	return splitLines(out)
+}
@@ -0,0 +1,149 @@
+/* Vuls - Vulnerability Scanner
+Copyright (C) 2016  Future Architect, Inc. Japan.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package scan
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseRPMQa(t *testing.T) {
+	out := "bash-5.1.8-4.fc34.x86_64\nopenssl-libs-1.1.1l-1.fc34.x86_64\n"
+	want := []string{"bash-5.1.8-4.fc34.x86_64", "openssl-libs-1.1.1l-1.fc34.x86_64"}
+	if got := parseRPMQa(out); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseRPMQa(%q) = %v, want %v", out, got, want)
+	}
+}
+
+func TestParseYumCheckUpdate(t *testing.T) {
+	out := `Loaded plugins: fastestmirror
+Loading mirror speeds from cached hostfile
+
+bash.x86_64    5.1.8-4.fc34    updates
+openssl-libs.x86_64    1.1.1l-1.fc34    updates
+
+Obsoleting Packages
+curl.x86_64    7.78.0-2.fc34    updates
+`
+	want := []string{"bash.x86_64", "openssl-libs.x86_64"}
+	if got := parseYumCheckUpdate(out); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseYumCheckUpdate(%q) = %v, want %v", out, got, want)
+	}
+}
+
+func TestParseDpkgQueryW(t *testing.T) {
+	out := "bash-5.1-2ubuntu3\nopenssl-1.1.1f-1ubuntu2\n"
+	want := []string{"bash-5.1-2ubuntu3", "openssl-1.1.1f-1ubuntu2"}
+	if got := parseDpkgQueryW(out); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseDpkgQueryW(%q) = %v, want %v", out, got, want)
+	}
+}
+
+func TestParseAptGetUpgradeDryRun(t *testing.T) {
+	out := `Reading package lists...
+Building dependency tree...
+Reading state information...
+Calculating upgrade...
+Inst bash [5.1-2ubuntu2] (5.1-2ubuntu3 Ubuntu:21.10/hirsute-updates [amd64])
+Inst openssl [1.1.1e-1] (1.1.1f-1ubuntu2 Ubuntu:21.10/hirsute-updates [amd64])
+Conf bash (5.1-2ubuntu3 Ubuntu:21.10/hirsute-updates [amd64])
+`
+	want := []string{"bash", "openssl"}
+	if got := parseAptGetUpgradeDryRun(out); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseAptGetUpgradeDryRun(%q) = %v, want %v", out, got, want)
+	}
+}
+
+func TestParsePkgQuery(t *testing.T) {
+	out := "curl-7.81.0\nopenssl-1.1.1m\n"
+	want := []string{"curl-7.81.0", "openssl-1.1.1m"}
+	if got := parsePkgQuery(out); !reflect.DeepEqual(got, want) {
+		t.Errorf("parsePkgQuery(%q) = %v, want %v", out, got, want)
+	}
+}
+
+func TestParsePkgUpgradeDryRun(t *testing.T) {
+	out := `Updating FreeBSD repository catalogue...
+Checking integrity... done (0 conflicting)
+The following 2 package(s) will be affected (of 0 checked):
+
+Installed packages to be UPGRADED:
+	curl: 7.80.0 -> 7.81.0
+	openssl: 1.1.1l -> 1.1.1m
+
+Number of packages to be upgraded: 2
+
+The process will require 5 MiB more space.
+4 MiB to be downloaded.
+`
+	want := []string{"curl", "openssl"}
+	if got := parsePkgUpgradeDryRun(out); !reflect.DeepEqual(got, want) {
+		t.Errorf("parsePkgUpgradeDryRun(%q) = %v, want %v", out, got, want)
+	}
+}
+
+func TestParsePkgUpgradeDryRunNothingToUpgrade(t *testing.T) {
+	out := "Checking integrity... done (0 conflicting)\nYour packages are up to date.\n"
+	if got := parsePkgUpgradeDryRun(out); len(got) != 0 {
+		t.Errorf("parsePkgUpgradeDryRun(%q) = %v, want none", out, got)
+	}
+}
+
+func TestParseApkInfoV(t *testing.T) {
+	out := "musl-1.2.2-r7\nbusybox-1.34.1-r3\n"
+	want := []string{"musl-1.2.2-r7", "busybox-1.34.1-r3"}
+	if got := parseApkInfoV(out); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseApkInfoV(%q) = %v, want %v", out, got, want)
+	}
+}
+
+func TestParseApkVersionList(t *testing.T) {
+	out := "Installed:\nmusl-1.2.2-r6<1.2.2-r7\nbusybox-1.34.1-r3<1.34.1-r5\n"
+	want := []string{"musl-1.2.2-r6<1.2.2-r7", "busybox-1.34.1-r3<1.34.1-r5"}
+	if got := parseApkVersionList(out); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseApkVersionList(%q) = %v, want %v", out, got, want)
+	}
+}
+
+func TestParseZypperListUpdates(t *testing.T) {
+	out := `S | Repository          | Name    | Current Version | Available Version | Arch
+--+---------------------+---------+------------------+--------------------+-------
+v | repo-update         | curl    | 7.66.0-3.1       | 7.66.0-5.1         | x86_64
+v | repo-update         | openssl | 1.1.1d-2.1       | 1.1.1d-4.1         | x86_64
+`
+	want := []string{"curl", "openssl"}
+	if got := parseZypperListUpdates(out); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseZypperListUpdates(%q) = %v, want %v", out, got, want)
+	}
+}
+
+func TestParseGetPackage(t *testing.T) {
+	out := "7zip-19.0.0\nGit-2.34.1\n"
+	want := []string{"7zip-19.0.0", "Git-2.34.1"}
+	if got := parseGetPackage(out); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseGetPackage(%q) = %v, want %v", out, got, want)
+	}
+}
+
+func TestParseGetWindowsUpdate(t *testing.T) {
+	out := "KB5008212\nKB5007253\n"
+	want := []string{"KB5008212", "KB5007253"}
+	if got := parseGetWindowsUpdate(out); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseGetWindowsUpdate(%q) = %v, want %v", out, got, want)
+	}
+}
@@ -0,0 +1,43 @@
+/* Vuls - Vulnerability Scanner
+Copyright (C) 2016  Future Architect, Inc. Japan.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package scan
+
+import "github.com/future-architect/vuls/config"
+
+// CpeNamesFor returns the CPE names to look up for s. For a pseudo
+// server (config.ServerTypePseudo) this is simply s.CpeNames, since
+// there's no SSH endpoint to enumerate packages on; for a normal
+// server it's s.CpeNames plus whatever NewOSFamily's package
+// enumeration discovers.
+func CpeNamesFor(s config.ServerInfo) ([]string, error) {
+	if s.IsPseudo() {
+		return s.CpeNames, nil
+	}
+
+	family, err := NewOSFamily(s)
+	if err != nil {
+		return nil, err
+	}
+
+	packages, err := family.InstalledPackages()
+	if err != nil {
+		return nil, err
+	}
+
+	return append(append([]string{}, s.CpeNames...), packages...), nil
+}
@@ -0,0 +1,40 @@
+/* Vuls - Vulnerability Scanner
+Copyright (C) 2016  Future Architect, Inc. Japan.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package scan
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/future-architect/vuls/config"
+)
+
+func TestCpeNamesForPseudoReturnsCpeNamesWithoutEnumeration(t *testing.T) {
+	s := config.ServerInfo{
+		Type:     config.ServerTypePseudo,
+		CpeNames: []string{"cpe:/a:example:widget:1.0", "cpe:/a:example:gadget:2.0"},
+	}
+
+	got, err := CpeNamesFor(s)
+	if err != nil {
+		t.Fatalf("CpeNamesFor() on a pseudo server = error %s, want no error", err)
+	}
+	if !reflect.DeepEqual(got, s.CpeNames) {
+		t.Errorf("CpeNamesFor() = %v, want %v", got, s.CpeNames)
+	}
+}
@@ -0,0 +1,186 @@
+/* Vuls - Vulnerability Scanner
+Copyright (C) 2016  Future Architect, Inc. Japan.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package util
+
+import (
+	"fmt"
+
+	"github.com/RackSec/srslog"
+	log "github.com/Sirupsen/logrus"
+
+	"github.com/future-architect/vuls/config"
+)
+
+// InitLog wires up logrus according to c: JSON vs text formatting, and
+// an RFC5424 syslog hook when c.Syslog is set, so remote SIEMs can
+// ingest scan events with proper severity/facility mapping.
+func InitLog(c config.LogConf) error {
+	if c.JSON {
+		log.SetFormatter(&log.JSONFormatter{})
+	} else {
+		log.SetFormatter(&log.TextFormatter{})
+	}
+
+	if c.Syslog == nil {
+		return nil
+	}
+
+	hook, err := newSyslogHook(c.Syslog)
+	if err != nil {
+		return fmt.Errorf("Failed to connect to syslog: %s", err)
+	}
+	log.AddHook(hook)
+	return nil
+}
+
+// syslogSeverityThreshold maps SyslogConf.Severity onto the logrus
+// level that's at least that severe, so e.g. Severity: "warning" means
+// only Warn/Error/Fatal/Panic entries are forwarded.
+var syslogSeverityThreshold = map[string]log.Level{
+	"emerg":   log.PanicLevel,
+	"alert":   log.FatalLevel,
+	"crit":    log.FatalLevel,
+	"err":     log.ErrorLevel,
+	"warning": log.WarnLevel,
+	"notice":  log.WarnLevel,
+	"info":    log.InfoLevel,
+	"debug":   log.DebugLevel,
+}
+
+// syslogHook is a logrus.Hook that forwards log entries at or above a
+// configured severity to a remote syslog daemon over RFC5424, mapping
+// each entry's logrus level onto a syslog severity under the configured
+// facility.
+type syslogHook struct {
+	writer *srslog.Writer
+	levels []log.Level
+}
+
+// syslogFacilities maps SyslogConf.Facility onto srslog's Priority
+// constants. Keep this in sync with config.syslogFacilities.
+var syslogFacilities = map[string]srslog.Priority{
+	"kern":     srslog.LOG_KERN,
+	"user":     srslog.LOG_USER,
+	"mail":     srslog.LOG_MAIL,
+	"daemon":   srslog.LOG_DAEMON,
+	"auth":     srslog.LOG_AUTH,
+	"syslog":   srslog.LOG_SYSLOG,
+	"lpr":      srslog.LOG_LPR,
+	"news":     srslog.LOG_NEWS,
+	"uucp":     srslog.LOG_UUCP,
+	"cron":     srslog.LOG_CRON,
+	"authpriv": srslog.LOG_AUTHPRIV,
+	"ftp":      srslog.LOG_FTP,
+	"local0":   srslog.LOG_LOCAL0,
+	"local1":   srslog.LOG_LOCAL1,
+	"local2":   srslog.LOG_LOCAL2,
+	"local3":   srslog.LOG_LOCAL3,
+	"local4":   srslog.LOG_LOCAL4,
+	"local5":   srslog.LOG_LOCAL5,
+	"local6":   srslog.LOG_LOCAL6,
+	"local7":   srslog.LOG_LOCAL7,
+}
+
+// syslogSeverities maps SyslogConf.Severity onto srslog's Priority
+// constants. Keep this in sync with config.syslogSeverities.
+var syslogSeverities = map[string]srslog.Priority{
+	"emerg":   srslog.LOG_EMERG,
+	"alert":   srslog.LOG_ALERT,
+	"crit":    srslog.LOG_CRIT,
+	"err":     srslog.LOG_ERR,
+	"warning": srslog.LOG_WARNING,
+	"notice":  srslog.LOG_NOTICE,
+	"info":    srslog.LOG_INFO,
+	"debug":   srslog.LOG_DEBUG,
+}
+
+func newSyslogHook(c *config.SyslogConf) (*syslogHook, error) {
+	severityName := defaultIfEmpty(c.Severity, "info")
+	facilityName := defaultIfEmpty(c.Facility, "user")
+
+	facility, ok := syslogFacilities[facilityName]
+	if !ok {
+		return nil, fmt.Errorf("Invalid syslog facility: %s", facilityName)
+	}
+	severity, ok := syslogSeverities[severityName]
+	if !ok {
+		return nil, fmt.Errorf("Invalid syslog severity: %s", severityName)
+	}
+
+	network, raddr := c.Protocol, ""
+	if len(c.Host) != 0 {
+		raddr = fmt.Sprintf("%s:%s", c.Host, c.Port)
+	}
+
+	writer, err := srslog.Dial(network, raddr, facility|severity, c.Tag)
+	if err != nil {
+		return nil, err
+	}
+	writer.SetFormatter(srslog.RFC5424Formatter)
+
+	return &syslogHook{
+		writer: writer,
+		levels: levelsAtOrAbove(syslogSeverityThreshold[severityName]),
+	}, nil
+}
+
+// levelsAtOrAbove returns the logrus levels at least as severe as
+// threshold. log.AllLevels is ordered from most to least severe.
+func levelsAtOrAbove(threshold log.Level) []log.Level {
+	var levels []log.Level
+	for _, l := range log.AllLevels {
+		levels = append(levels, l)
+		if l == threshold {
+			break
+		}
+	}
+	return levels
+}
+
+// Levels reports which logrus levels this hook fires for, gated by the
+// configured Log.Syslog.Severity threshold.
+func (h *syslogHook) Levels() []log.Level {
+	return h.levels
+}
+
+func (h *syslogHook) Fire(entry *log.Entry) error {
+	line, err := entry.String()
+	if err != nil {
+		return err
+	}
+
+	switch entry.Level {
+	case log.PanicLevel, log.FatalLevel:
+		return h.writer.Crit(line)
+	case log.ErrorLevel:
+		return h.writer.Err(line)
+	case log.WarnLevel:
+		return h.writer.Warning(line)
+	case log.InfoLevel:
+		return h.writer.Info(line)
+	default:
+		return h.writer.Debug(line)
+	}
+}
+
+func defaultIfEmpty(s, def string) string {
+	if len(s) == 0 {
+		return def
+	}
+	return s
+}
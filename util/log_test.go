@@ -0,0 +1,61 @@
+/* Vuls - Vulnerability Scanner
+Copyright (C) 2016  Future Architect, Inc. Japan.
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package util
+
+import (
+	"testing"
+
+	log "github.com/Sirupsen/logrus"
+
+	"github.com/future-architect/vuls/config"
+)
+
+func TestLevelsAtOrAboveWarning(t *testing.T) {
+	got := levelsAtOrAbove(log.WarnLevel)
+	want := []log.Level{log.PanicLevel, log.FatalLevel, log.ErrorLevel, log.WarnLevel}
+
+	if len(got) != len(want) {
+		t.Fatalf("levelsAtOrAbove(WarnLevel) = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("levelsAtOrAbove(WarnLevel) = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestLevelsAtOrAboveDebugIncludesEverything(t *testing.T) {
+	got := levelsAtOrAbove(log.DebugLevel)
+	if len(got) != 6 {
+		t.Errorf("levelsAtOrAbove(DebugLevel) = %v, want all 6 non-trace levels", got)
+	}
+}
+
+func TestNewSyslogHookRejectsUnknownFacility(t *testing.T) {
+	_, err := newSyslogHook(&config.SyslogConf{Facility: "not-a-facility"})
+	if err == nil {
+		t.Error("newSyslogHook() with an invalid facility should return an error")
+	}
+}
+
+func TestNewSyslogHookRejectsUnknownSeverity(t *testing.T) {
+	_, err := newSyslogHook(&config.SyslogConf{Severity: "not-a-severity"})
+	if err == nil {
+		t.Error("newSyslogHook() with an invalid severity should return an error")
+	}
+}